@@ -0,0 +1,164 @@
+// Package androidenv locates the Android SDK, a JDK, and the NDK on the
+// host machine, probing the OS-conventional install locations when the
+// usual environment variables aren't set (mirroring golang.org/x/mobile's
+// sdkpath discovery).
+package androidenv
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// DetectSDK locates the Android SDK root. It checks ANDROID_HOME and
+// ANDROID_SDK_ROOT first, then falls back to the OS-conventional install
+// location ($HOME/Library/Android/sdk on darwin, %LOCALAPPDATA%\Android\Sdk
+// on windows, $HOME/Android/Sdk elsewhere). A candidate is only accepted if
+// it actually looks like an SDK: it must contain platform-tools/adb or a
+// cmdline-tools directory.
+//
+// source identifies which of those places produced path, so callers can
+// report it (e.g. "ANDROID_HOME", "ANDROID_SDK_ROOT", "default location").
+func DetectSDK() (path, source string, err error) {
+	for _, c := range []struct{ path, source string }{
+		{os.Getenv("ANDROID_HOME"), "ANDROID_HOME"},
+		{os.Getenv("ANDROID_SDK_ROOT"), "ANDROID_SDK_ROOT"},
+	} {
+		if c.path != "" && looksLikeSDK(c.path) {
+			return c.path, c.source, nil
+		}
+	}
+
+	for _, p := range defaultSDKPaths() {
+		if looksLikeSDK(p) {
+			return p, "default location", nil
+		}
+	}
+
+	return "", "", fmt.Errorf("androidenv: no Android SDK found (checked ANDROID_HOME, ANDROID_SDK_ROOT, and the default install location for %s)", runtime.GOOS)
+}
+
+func defaultSDKPaths() []string {
+	switch runtime.GOOS {
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return []string{filepath.Join(local, "Android", "Sdk")}
+		}
+		return nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return nil
+		}
+		return []string{filepath.Join(home, "Library", "Android", "sdk")}
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return nil
+		}
+		return []string{filepath.Join(home, "Android", "Sdk")}
+	}
+}
+
+// looksLikeSDK reports whether dir contains the markers of a real SDK
+// install, so a stale or empty ANDROID_HOME doesn't get reported as found.
+func looksLikeSDK(dir string) bool {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+
+	adb := "adb"
+	if runtime.GOOS == "windows" {
+		adb = "adb.exe"
+	}
+	if info, err := os.Stat(filepath.Join(dir, "platform-tools", adb)); err == nil && !info.IsDir() {
+		return true
+	}
+	if info, err := os.Stat(filepath.Join(dir, "cmdline-tools")); err == nil && info.IsDir() {
+		return true
+	}
+	return false
+}
+
+// DetectJDK locates a java executable suitable for running Gradle. It
+// checks JAVA_HOME first, then falls back to java on PATH.
+func DetectJDK() (path, source string, err error) {
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		javaBin := "java"
+		if runtime.GOOS == "windows" {
+			javaBin = "java.exe"
+		}
+		candidate := filepath.Join(home, "bin", javaBin)
+		if info, statErr := os.Stat(candidate); statErr == nil && !info.IsDir() {
+			return candidate, "JAVA_HOME", nil
+		}
+	}
+
+	if p, err := exec.LookPath("java"); err == nil {
+		return p, "PATH", nil
+	}
+
+	return "", "", fmt.Errorf("androidenv: java not found (checked JAVA_HOME and PATH)")
+}
+
+// DetectNDK locates a side-by-side NDK install under the detected SDK's
+// ndk/ directory (ANDROID_NDK_HOME is checked first). When more than one
+// version is installed, the lexicographically highest -- which for NDK's
+// "MAJOR.MINOR.PATCH" directory names is also the newest -- is returned.
+func DetectNDK() (path, source string, err error) {
+	if home := os.Getenv("ANDROID_NDK_HOME"); home != "" {
+		if info, err := os.Stat(home); err == nil && info.IsDir() {
+			return home, "ANDROID_NDK_HOME", nil
+		}
+	}
+
+	sdkPath, sdkSource, err := DetectSDK()
+	if err != nil {
+		return "", "", fmt.Errorf("androidenv: no Android NDK found (checked ANDROID_NDK_HOME and <sdk>/ndk): %w", err)
+	}
+
+	ndkRoot := filepath.Join(sdkPath, "ndk")
+	entries, err := os.ReadDir(ndkRoot)
+	if err != nil || len(entries) == 0 {
+		return "", "", fmt.Errorf("androidenv: no Android NDK found under %s", ndkRoot)
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			versions = append(versions, e.Name())
+		}
+	}
+	if len(versions) == 0 {
+		return "", "", fmt.Errorf("androidenv: no Android NDK found under %s", ndkRoot)
+	}
+	sort.Strings(versions)
+	latest := versions[len(versions)-1]
+
+	return filepath.Join(ndkRoot, latest), fmt.Sprintf("SDK (%s)", sdkSource), nil
+}
+
+// WriteLocalProperties writes a local.properties file into projectDir
+// pointing sdk.dir at sdkPath, so Gradle can locate the SDK even when the
+// shell that eventually runs gradlew doesn't have ANDROID_HOME set.
+func WriteLocalProperties(projectDir, sdkPath string) error {
+	content := fmt.Sprintf("sdk.dir=%s\n", escapeProperty(sdkPath))
+	return os.WriteFile(filepath.Join(projectDir, "local.properties"), []byte(content), 0644)
+}
+
+// escapeProperty escapes backslashes for Java's .properties format, which
+// matters on Windows where sdkPath contains them natively.
+func escapeProperty(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			out = append(out, '\\', '\\')
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
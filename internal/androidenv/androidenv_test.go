@@ -0,0 +1,94 @@
+package androidenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetectSDK_FromEnv(t *testing.T) {
+	sdk := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sdk, "cmdline-tools"), 0755); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	t.Setenv("ANDROID_HOME", sdk)
+	t.Setenv("ANDROID_SDK_ROOT", "")
+
+	path, source, err := DetectSDK()
+	if err != nil {
+		t.Fatalf("DetectSDK: %v", err)
+	}
+	if path != sdk {
+		t.Errorf("path = %q, want %q", path, sdk)
+	}
+	if source != "ANDROID_HOME" {
+		t.Errorf("source = %q, want %q", source, "ANDROID_HOME")
+	}
+}
+
+func TestDetectSDK_RejectsEnvWithoutMarkers(t *testing.T) {
+	empty := t.TempDir()
+	t.Setenv("ANDROID_HOME", empty)
+	t.Setenv("ANDROID_SDK_ROOT", "")
+
+	if _, _, err := DetectSDK(); err == nil {
+		t.Fatal("expected an error for an ANDROID_HOME without SDK markers, got nil")
+	}
+}
+
+func TestDetectNDK_FromSDK(t *testing.T) {
+	sdk := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sdk, "cmdline-tools"), 0755); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	for _, v := range []string{"25.2.9519653", "26.1.10909125"} {
+		if err := os.MkdirAll(filepath.Join(sdk, "ndk", v), 0755); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	t.Setenv("ANDROID_HOME", sdk)
+	t.Setenv("ANDROID_SDK_ROOT", "")
+	t.Setenv("ANDROID_NDK_HOME", "")
+
+	path, source, err := DetectNDK()
+	if err != nil {
+		t.Fatalf("DetectNDK: %v", err)
+	}
+	want := filepath.Join(sdk, "ndk", "26.1.10909125")
+	if path != want {
+		t.Errorf("path = %q, want %q (highest version)", path, want)
+	}
+	if source != "SDK (ANDROID_HOME)" {
+		t.Errorf("source = %q, want %q", source, "SDK (ANDROID_HOME)")
+	}
+}
+
+func TestWriteLocalProperties(t *testing.T) {
+	dir := t.TempDir()
+	sdkPath := filepath.Join(dir, "sdk")
+
+	if err := WriteLocalProperties(dir, sdkPath); err != nil {
+		t.Fatalf("WriteLocalProperties: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "local.properties"))
+	if err != nil {
+		t.Fatalf("reading local.properties: %v", err)
+	}
+	want := "sdk.dir=" + escapeProperty(sdkPath) + "\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("backslash escaping only matters for windows-style paths")
+	}
+	if got := escapeProperty(`C:\Android\Sdk`); got != `C:\\Android\\Sdk` {
+		t.Errorf("got %q", got)
+	}
+}
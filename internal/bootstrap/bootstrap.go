@@ -0,0 +1,95 @@
+// Package bootstrap runs the post-generation steps that turn a freshly
+// scaffolded project into one Gradle can actually build: materializing the
+// wrapper jar/properties at the requested Gradle version and, optionally,
+// priming the Gradle daemon with a first task.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/yahyatinani/droid-cli/internal/androidenv"
+)
+
+// Options configures a bootstrap Run.
+type Options struct {
+	// GradleVersion is the version materialized by `gradlew wrapper
+	// --gradle-version`.
+	GradleVersion string
+	// Task is the Gradle task run after the wrapper is materialized, e.g.
+	// "help" to just prime the daemon or "assembleDebug" for a first
+	// build. Empty skips this step.
+	Task string
+	// Stdout and Stderr receive the live wrapper/task output. Both
+	// default to os.Stdout/os.Stderr.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run chmods the wrapper script, regenerates gradle-wrapper.jar and
+// gradle-wrapper.properties at opts.GradleVersion, and, if opts.Task is
+// set, runs that task -- streaming both steps' output live. It fails fast
+// with a clear message if java or the Android SDK can't be found.
+func Run(ctx context.Context, projectDir string, opts Options) error {
+	if _, _, err := androidenv.DetectJDK(); err != nil {
+		return fmt.Errorf("bootstrap: %w", err)
+	}
+	if _, _, err := androidenv.DetectSDK(); err != nil {
+		return fmt.Errorf("bootstrap: %w", err)
+	}
+
+	wrapperPath := filepath.Join(projectDir, wrapperScript())
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(wrapperPath, 0755); err != nil {
+			return fmt.Errorf("bootstrap: chmod %s: %w", wrapperScript(), err)
+		}
+	}
+
+	fmt.Fprintf(opts.stdout(), "🔧 Materializing Gradle wrapper %s...\n", opts.GradleVersion)
+	if err := run(ctx, projectDir, opts, "wrapper", "--gradle-version", opts.GradleVersion); err != nil {
+		return fmt.Errorf("bootstrap: materializing wrapper: %w", err)
+	}
+
+	if opts.Task != "" {
+		fmt.Fprintf(opts.stdout(), "🔧 Running ./%s %s...\n", wrapperScript(), opts.Task)
+		if err := run(ctx, projectDir, opts, opts.Task); err != nil {
+			return fmt.Errorf("bootstrap: running %s: %w", opts.Task, err)
+		}
+	}
+
+	return nil
+}
+
+func wrapperScript() string {
+	if runtime.GOOS == "windows" {
+		return "gradlew.bat"
+	}
+	return "gradlew"
+}
+
+func run(ctx context.Context, projectDir string, opts Options, args ...string) error {
+	cmd := exec.CommandContext(ctx, filepath.Join(".", wrapperScript()), args...)
+	cmd.Dir = projectDir
+	cmd.Stdout = opts.stdout()
+	cmd.Stderr = opts.stderr()
+	return cmd.Run()
+}
+
+func (o Options) stdout() io.Writer {
+	if o.Stdout != nil {
+		return o.Stdout
+	}
+	return os.Stdout
+}
+
+func (o Options) stderr() io.Writer {
+	if o.Stderr != nil {
+		return o.Stderr
+	}
+	return os.Stderr
+}
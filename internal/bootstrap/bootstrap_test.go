@@ -0,0 +1,59 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func clearAndroidEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("JAVA_HOME", "")
+	t.Setenv("ANDROID_HOME", "")
+	t.Setenv("ANDROID_SDK_ROOT", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+}
+
+func TestRun_FailsFastWhenJDKNotFound(t *testing.T) {
+	clearAndroidEnv(t)
+
+	err := Run(context.Background(), t.TempDir(), Options{GradleVersion: "9.2.1"})
+	if err == nil {
+		t.Fatal("expected an error when no JDK can be found, got nil")
+	}
+}
+
+func TestRun_FailsFastWhenSDKNotFound(t *testing.T) {
+	clearAndroidEnv(t)
+
+	javaHome := t.TempDir()
+	javaBin := "java"
+	if runtime.GOOS == "windows" {
+		javaBin = "java.exe"
+	}
+	if err := os.MkdirAll(filepath.Join(javaHome, "bin"), 0755); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(javaHome, "bin", javaBin), []byte(""), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	t.Setenv("JAVA_HOME", javaHome)
+
+	err := Run(context.Background(), t.TempDir(), Options{GradleVersion: "9.2.1"})
+	if err == nil {
+		t.Fatal("expected an error when no Android SDK can be found, got nil")
+	}
+}
+
+func TestWrapperScript(t *testing.T) {
+	want := "gradlew"
+	if runtime.GOOS == "windows" {
+		want = "gradlew.bat"
+	}
+	if got := wrapperScript(); got != want {
+		t.Errorf("wrapperScript() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+// Package config resolves the answers droid-cli needs to generate a
+// project (app name, package, min SDK, ...) from whatever combination of
+// CLI flags, environment variables, and a YAML/JSON manifest the caller
+// supplies, so main can run fully headless in CI.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the fully resolved set of answers needed to generate a project.
+type Config struct {
+	AppName     string       `yaml:"app_name"`
+	PackageName string       `yaml:"package"`
+	MinSdk      string       `yaml:"min_sdk"`
+	Output      string       `yaml:"output"`
+	Features    []string     `yaml:"features"`
+	Modules     []ModuleSpec `yaml:"modules"`
+	Yes         bool         `yaml:"yes"`
+}
+
+// ModuleType enumerates the kinds of Gradle module a multi-module project
+// can scaffold.
+type ModuleType string
+
+const (
+	ModuleApp     ModuleType = "app"
+	ModuleLibrary ModuleType = "library"
+	ModuleFeature ModuleType = "feature"
+)
+
+// ModuleSpec describes one module of a multi-module project: its Gradle
+// project name, its type, and the other modules it depends on via
+// implementation(project(":...")).
+type ModuleSpec struct {
+	Name      string     `yaml:"name"`
+	Type      ModuleType `yaml:"type"`
+	DependsOn []string   `yaml:"depends_on"`
+}
+
+// Defaults mirrors what the interactive survey has always pre-filled.
+var Defaults = Config{
+	AppName:     "Mad",
+	PackageName: "com.example.myapp",
+	MinSdk:      "24",
+}
+
+// Flags carries the raw values collected from command-line flags. A zero
+// value (empty string, false) means "not set on the command line" -- a
+// flag never clobbers an env/file value with a blank.
+type Flags struct {
+	AppName     string
+	PackageName string
+	MinSdk      string
+	Output      string
+	Features    string // comma-separated, e.g. "hilt,room,nav"
+	Modules     string // comma-separated "name[:type]", e.g. "app,core-ui,data:library"
+	Yes         bool
+	ConfigFile  string
+}
+
+// envPrefix namespaces every environment variable Resolve looks at.
+const envPrefix = "DROID_CLI_"
+
+// Resolve merges configuration from, in increasing priority: built-in
+// Defaults, an optional YAML/JSON manifest, environment variables, and
+// command-line flags. file is the manifest path to fall back to when
+// flags.ConfigFile is empty; pass "" if there's no default location.
+// env is the environment lookup (normally os.Getenv; tests inject a fake).
+//
+// Alongside the merged Config, Resolve reports which layer ("flag", "env",
+// "file", or "default") supplied each field's value, so callers such as
+// main's survey step know which questions can be skipped.
+func Resolve(flags Flags, file string, env func(string) string) (Config, map[string]string, error) {
+	cfg := Defaults
+	sources := map[string]string{
+		"AppName":     "default",
+		"PackageName": "default",
+		"MinSdk":      "default",
+		"Output":      "default",
+		"Features":    "default",
+		"Modules":     "default",
+		"Yes":         "default",
+	}
+
+	configPath := flags.ConfigFile
+	if configPath == "" {
+		configPath = file
+	}
+	if configPath != "" {
+		fileCfg, err := loadFile(configPath)
+		if err != nil {
+			return Config{}, nil, fmt.Errorf("config: %w", err)
+		}
+		apply(&cfg, sources, "file", fileCfg)
+	}
+
+	envModules, err := ParseModules(env(envPrefix + "MODULES"))
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("config: %s: %w", envPrefix+"MODULES", err)
+	}
+	apply(&cfg, sources, "env", Config{
+		AppName:     env(envPrefix + "APP_NAME"),
+		PackageName: env(envPrefix + "PACKAGE"),
+		MinSdk:      env(envPrefix + "MIN_SDK"),
+		Output:      env(envPrefix + "OUTPUT"),
+		Features:    splitFeatures(env(envPrefix + "FEATURES")),
+		Modules:     envModules,
+		Yes:         env(envPrefix+"YES") == "true",
+	})
+
+	flagModules, err := ParseModules(flags.Modules)
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("config: --modules: %w", err)
+	}
+	apply(&cfg, sources, "flag", Config{
+		AppName:     flags.AppName,
+		PackageName: flags.PackageName,
+		MinSdk:      flags.MinSdk,
+		Output:      flags.Output,
+		Features:    splitFeatures(flags.Features),
+		Modules:     flagModules,
+		Yes:         flags.Yes,
+	})
+
+	return cfg, sources, nil
+}
+
+// apply overlays every non-zero field of patch onto base, recording name as
+// the field's source whenever it overrides something.
+func apply(base *Config, sources map[string]string, name string, patch Config) {
+	if patch.AppName != "" {
+		base.AppName = patch.AppName
+		sources["AppName"] = name
+	}
+	if patch.PackageName != "" {
+		base.PackageName = patch.PackageName
+		sources["PackageName"] = name
+	}
+	if patch.MinSdk != "" {
+		base.MinSdk = patch.MinSdk
+		sources["MinSdk"] = name
+	}
+	if patch.Output != "" {
+		base.Output = patch.Output
+		sources["Output"] = name
+	}
+	if len(patch.Features) > 0 {
+		base.Features = patch.Features
+		sources["Features"] = name
+	}
+	if len(patch.Modules) > 0 {
+		base.Modules = patch.Modules
+		sources["Modules"] = name
+	}
+	if patch.Yes {
+		base.Yes = true
+		sources["Yes"] = name
+	}
+}
+
+func splitFeatures(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// ParseModules parses the compact "name[:type]" list used by --modules and
+// DROID_CLI_MODULES, e.g. "app,core-ui,data:library,domain:library". A
+// module without an explicit type defaults to ModuleApp when its name is
+// "app" and ModuleLibrary otherwise. DependsOn isn't expressible in this
+// compact form; set it via a --config manifest instead.
+func ParseModules(s string) ([]ModuleSpec, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var modules []ModuleSpec
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, typ, hasType := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("module entry %q is missing a name", entry)
+		}
+
+		moduleType := ModuleLibrary
+		if name == "app" {
+			moduleType = ModuleApp
+		}
+		if hasType {
+			switch ModuleType(strings.TrimSpace(typ)) {
+			case ModuleApp:
+				moduleType = ModuleApp
+			case ModuleLibrary:
+				moduleType = ModuleLibrary
+			case ModuleFeature:
+				moduleType = ModuleFeature
+			default:
+				return nil, fmt.Errorf("module %q: unknown type %q (want app, library, or feature)", name, typ)
+			}
+		}
+
+		modules = append(modules, ModuleSpec{Name: name, Type: moduleType})
+	}
+	return modules, nil
+}
+
+func loadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	// yaml.Unmarshal also accepts JSON, since JSON is a subset of YAML, so
+	// a single code path handles both the YAML and JSON manifests the
+	// --config flag documents.
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
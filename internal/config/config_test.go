@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func noEnv(string) string { return "" }
+
+func TestResolve_Defaults(t *testing.T) {
+	cfg, sources, err := Resolve(Flags{}, "", noEnv)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Defaults) {
+		t.Fatalf("expected Defaults, got %+v", cfg)
+	}
+	for field, source := range sources {
+		if source != "default" {
+			t.Errorf("field %s: expected source %q, got %q", field, "default", source)
+		}
+	}
+}
+
+func TestResolve_FilePrecedence(t *testing.T) {
+	file := writeManifest(t, `app_name: FromFile
+package: com.file.app
+min_sdk: "30"
+`)
+
+	cfg, sources, err := Resolve(Flags{}, file, noEnv)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if cfg.AppName != "FromFile" || cfg.PackageName != "com.file.app" || cfg.MinSdk != "30" {
+		t.Fatalf("file values not applied: %+v", cfg)
+	}
+	if sources["AppName"] != "file" {
+		t.Errorf("expected AppName source %q, got %q", "file", sources["AppName"])
+	}
+}
+
+func TestResolve_EnvOverridesFile(t *testing.T) {
+	file := writeManifest(t, `app_name: FromFile
+`)
+	env := func(key string) string {
+		if key == "DROID_CLI_APP_NAME" {
+			return "FromEnv"
+		}
+		return ""
+	}
+
+	cfg, sources, err := Resolve(Flags{}, file, env)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if cfg.AppName != "FromEnv" {
+		t.Fatalf("expected env to win, got %q", cfg.AppName)
+	}
+	if sources["AppName"] != "env" {
+		t.Errorf("expected AppName source %q, got %q", "env", sources["AppName"])
+	}
+}
+
+func TestResolve_FlagOverridesEnvAndFile(t *testing.T) {
+	file := writeManifest(t, `app_name: FromFile
+`)
+	env := func(key string) string {
+		if key == "DROID_CLI_APP_NAME" {
+			return "FromEnv"
+		}
+		return ""
+	}
+	flags := Flags{AppName: "FromFlag"}
+
+	cfg, sources, err := Resolve(flags, file, env)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if cfg.AppName != "FromFlag" {
+		t.Fatalf("expected flag to win, got %q", cfg.AppName)
+	}
+	if sources["AppName"] != "flag" {
+		t.Errorf("expected AppName source %q, got %q", "flag", sources["AppName"])
+	}
+	// Untouched fields still fall back through the chain.
+	if cfg.MinSdk != Defaults.MinSdk {
+		t.Errorf("expected MinSdk default, got %q", cfg.MinSdk)
+	}
+}
+
+func TestResolve_ConfigFlagOverridesDefaultFilePath(t *testing.T) {
+	defaultFile := writeManifest(t, `app_name: FromDefaultPath
+`)
+	explicitFile := writeManifest(t, `app_name: FromExplicitFlag
+`)
+
+	cfg, _, err := Resolve(Flags{ConfigFile: explicitFile}, defaultFile, noEnv)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if cfg.AppName != "FromExplicitFlag" {
+		t.Fatalf("expected --config to win over the default path, got %q", cfg.AppName)
+	}
+}
+
+func TestResolve_FeaturesFlagSplitOnCommas(t *testing.T) {
+	flags := Flags{Features: "hilt,room"}
+	cfg, sources, err := Resolve(flags, "", noEnv)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(cfg.Features) != 2 || cfg.Features[0] != "hilt" || cfg.Features[1] != "room" {
+		t.Fatalf("expected [hilt room], got %v", cfg.Features)
+	}
+	if sources["Features"] != "flag" {
+		t.Errorf("expected Features source %q, got %q", "flag", sources["Features"])
+	}
+}
+
+func writeManifest(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "droid-cli.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+	return path
+}
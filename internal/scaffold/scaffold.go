@@ -0,0 +1,197 @@
+// Package scaffold generates a multi-module project: one build.gradle.kts
+// per non-app module rendered from templates/_modules/<type>, the matching
+// include(...) statements in settings.gradle.kts, and a shared
+// gradle/libs.versions.toml version catalog. The app module is the root
+// project and is left to the base template.
+package scaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yahyatinani/droid-cli/internal/config"
+	"github.com/yahyatinani/droid-cli/internal/templating"
+)
+
+// Validate checks that modules form a usable multi-module project: no
+// duplicate names, exactly one app-type module, every DependsOn entry
+// points at a module that exists, and the dependency graph is acyclic.
+func Validate(modules []config.ModuleSpec) error {
+	if len(modules) == 0 {
+		return fmt.Errorf("scaffold: no modules given")
+	}
+
+	byName := make(map[string]config.ModuleSpec, len(modules))
+	appCount := 0
+	for _, m := range modules {
+		if _, dup := byName[m.Name]; dup {
+			return fmt.Errorf("scaffold: duplicate module name %q", m.Name)
+		}
+		byName[m.Name] = m
+		if m.Type == config.ModuleApp {
+			appCount++
+		}
+	}
+	if appCount != 1 {
+		return fmt.Errorf("scaffold: expected exactly one app-type module, found %d", appCount)
+	}
+
+	for _, m := range modules {
+		for _, dep := range m.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("scaffold: module %q depends on unknown module %q", m.Name, dep)
+			}
+		}
+	}
+
+	return detectCycle(byName)
+}
+
+// detectCycle runs a DFS over the DependsOn graph, reporting the first
+// cycle found as a readable "a -> b -> a" chain.
+func detectCycle(byName map[string]config.ModuleSpec) error {
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(byName))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("scaffold: dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		}
+		state[name] = visiting
+		for _, dep := range byName[name].DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+
+	for name := range byName {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ModuleData is what a module's build.gradle.kts template renders against,
+// on top of the project-wide templating.Data.
+type ModuleData struct {
+	templating.Data
+	Module config.ModuleSpec
+}
+
+// Generate validates modules, then renders one directory per non-app module
+// under projectDir, registers those modules in settings.gradle.kts, and
+// writes the shared gradle/libs.versions.toml catalog. App-type modules are
+// neither rendered nor registered.
+func Generate(set *templating.Set, templateFS fs.FS, projectDir string, modules []config.ModuleSpec, data templating.Data) error {
+	if err := Validate(modules); err != nil {
+		return err
+	}
+
+	for _, m := range modules {
+		if m.Type == config.ModuleApp {
+			// The app module is the root project; main.go's per-file walk
+			// already rendered its build.gradle.kts (with the feature-aware
+			// {{if .Features.Hilt}} dependency blocks), so don't clobber it
+			// with the generic module template.
+			continue
+		}
+		if err := generateModule(set, templateFS, projectDir, m, data); err != nil {
+			return fmt.Errorf("scaffold: module %q: %w", m.Name, err)
+		}
+	}
+
+	if err := registerModules(projectDir, modules); err != nil {
+		return err
+	}
+
+	return writeVersionCatalog(projectDir, data)
+}
+
+func generateModule(set *templating.Set, templateFS fs.FS, projectDir string, m config.ModuleSpec, data templating.Data) error {
+	moduleDir := filepath.Join(projectDir, m.Name)
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		return err
+	}
+
+	templatePath := fmt.Sprintf("templates/_modules/%s/build.gradle.kts.tmpl", m.Type)
+	body, err := fs.ReadFile(templateFS, templatePath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", templatePath, err)
+	}
+
+	rendered, err := set.Render(templatePath, body, ModuleData{Data: data, Module: m})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(moduleDir, "build.gradle.kts"), rendered, 0644)
+}
+
+// registerModules appends an include(":name") line per non-app module to
+// the project's existing settings.gradle.kts. The app module is the root
+// project and is already wired by the base template.
+func registerModules(projectDir string, modules []config.ModuleSpec) error {
+	path := filepath.Join(projectDir, "settings.gradle.kts")
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var includes strings.Builder
+	for _, m := range modules {
+		if m.Type == config.ModuleApp {
+			continue
+		}
+		fmt.Fprintf(&includes, "include(\":%s\")\n", m.Name)
+	}
+	if includes.Len() == 0 {
+		return nil
+	}
+
+	updated := append(existing, '\n')
+	updated = append(updated, []byte(includes.String())...)
+	return os.WriteFile(path, updated, 0644)
+}
+
+// writeVersionCatalog writes gradle/libs.versions.toml so every module
+// reads its Gradle/Kotlin/AGP/Compose versions from one shared catalog
+// instead of hardcoding them per module.
+func writeVersionCatalog(projectDir string, data templating.Data) error {
+	catalogDir := filepath.Join(projectDir, "gradle")
+	if err := os.MkdirAll(catalogDir, 0755); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf(`[versions]
+agp = "%s"
+kotlin = "%s"
+gradle = "%s"
+composeBom = "%s"
+minSdk = "%s"
+
+[libraries]
+compose-bom = { module = "androidx.compose:compose-bom", version.ref = "composeBom" }
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+android-library = { id = "com.android.library", version.ref = "agp" }
+kotlin-android = { id = "org.jetbrains.kotlin.android", version.ref = "kotlin" }
+`, data.AGPVersion, data.KotlinVersion, data.GradleVersion, data.ComposeBomVersion, data.MinSdk)
+
+	return os.WriteFile(filepath.Join(catalogDir, "libs.versions.toml"), []byte(content), 0644)
+}
@@ -0,0 +1,144 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yahyatinani/droid-cli/internal/config"
+	"github.com/yahyatinani/droid-cli/internal/templating"
+)
+
+func TestValidate_RequiresExactlyOneAppModule(t *testing.T) {
+	cases := []struct {
+		name    string
+		modules []config.ModuleSpec
+		wantErr string
+	}{
+		{
+			name:    "no app module",
+			modules: []config.ModuleSpec{{Name: "core-ui", Type: config.ModuleLibrary}},
+			wantErr: "exactly one app-type module",
+		},
+		{
+			name: "two app modules",
+			modules: []config.ModuleSpec{
+				{Name: "app", Type: config.ModuleApp},
+				{Name: "app2", Type: config.ModuleApp},
+			},
+			wantErr: "exactly one app-type module",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.modules)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DetectsCycle(t *testing.T) {
+	modules := []config.ModuleSpec{
+		{Name: "app", Type: config.ModuleApp, DependsOn: []string{"domain"}},
+		{Name: "domain", Type: config.ModuleLibrary, DependsOn: []string{"data"}},
+		{Name: "data", Type: config.ModuleLibrary, DependsOn: []string{"domain"}},
+	}
+
+	err := Validate(modules)
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("Validate() = %v, want a dependency cycle error", err)
+	}
+}
+
+func TestValidate_RejectsUnknownDependency(t *testing.T) {
+	modules := []config.ModuleSpec{
+		{Name: "app", Type: config.ModuleApp, DependsOn: []string{"missing"}},
+	}
+
+	err := Validate(modules)
+	if err == nil || !strings.Contains(err.Error(), "unknown module") {
+		t.Fatalf("Validate() = %v, want an unknown-module error", err)
+	}
+}
+
+func TestValidate_AcceptsAcyclicGraph(t *testing.T) {
+	modules := []config.ModuleSpec{
+		{Name: "app", Type: config.ModuleApp, DependsOn: []string{"core-ui", "domain"}},
+		{Name: "core-ui", Type: config.ModuleLibrary},
+		{Name: "domain", Type: config.ModuleLibrary, DependsOn: []string{"data"}},
+		{Name: "data", Type: config.ModuleLibrary},
+	}
+
+	if err := Validate(modules); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+}
+
+// TestGenerate_DoesNotClobberAppModule guards against Generate overwriting
+// the app module's build.gradle.kts, which main.go's per-file walk already
+// rendered with feature-aware dependency blocks before Generate ever runs.
+func TestGenerate_DoesNotClobberAppModule(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"templates/_modules/library/build.gradle.kts.tmpl": &fstest.MapFile{
+			Data: []byte(`plugins { id("com.android.library") }
+`),
+		},
+	}
+	set, err := templating.NewSet(templateFS)
+	if err != nil {
+		t.Fatalf("NewSet: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	appDir := filepath.Join(projectDir, "app")
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	const appBuildFile = `plugins { id("com.android.application") }
+dependencies { implementation("com.google.dagger:hilt-android") }
+`
+	if err := os.WriteFile(filepath.Join(appDir, "build.gradle.kts"), []byte(appBuildFile), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "settings.gradle.kts"), []byte("rootProject.name = \"Mad\"\n"), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	modules := []config.ModuleSpec{
+		{Name: "app", Type: config.ModuleApp},
+		{Name: "core-ui", Type: config.ModuleLibrary},
+	}
+
+	if err := Generate(set, templateFS, projectDir, modules, templating.Data{}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(appDir, "build.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading app/build.gradle.kts: %v", err)
+	}
+	if string(got) != appBuildFile {
+		t.Errorf("app/build.gradle.kts was overwritten:\ngot:  %q\nwant: %q", got, appBuildFile)
+	}
+
+	libBuild, err := os.ReadFile(filepath.Join(projectDir, "core-ui", "build.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading core-ui/build.gradle.kts: %v", err)
+	}
+	if !strings.Contains(string(libBuild), "com.android.library") {
+		t.Errorf("core-ui/build.gradle.kts = %q, want the library template rendered", libBuild)
+	}
+
+	settings, err := os.ReadFile(filepath.Join(projectDir, "settings.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading settings.gradle.kts: %v", err)
+	}
+	if !strings.Contains(string(settings), `include(":core-ui")`) {
+		t.Errorf("settings.gradle.kts = %q, want core-ui included", settings)
+	}
+}
@@ -0,0 +1,158 @@
+// Package templating renders the files under templates/ with Go's
+// text/template instead of naive string substitution, so a file can
+// conditionally include dependencies, plugins, and imports based on which
+// features the project was generated with (e.g. {{if .Features.Hilt}}).
+package templating
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// FeatureSet toggles the optional libraries a generated project can wire
+// in: Hilt for DI, Room for local persistence, Retrofit for networking, and
+// Nav for Jetpack Navigation.
+type FeatureSet struct {
+	Hilt     bool
+	Room     bool
+	Retrofit bool
+	Nav      bool
+}
+
+// Data is everything a file under templates/ can reference.
+type Data struct {
+	AppName           string
+	PackageName       string
+	MinSdk            string
+	GradleVersion     string
+	AGPVersion        string
+	KotlinVersion     string
+	ComposeBomVersion string
+	Features          FeatureSet
+}
+
+// PartialsDir holds shared fragments -- per-feature Gradle dependency
+// blocks, plugin declarations, and the like -- that ordinary files pull in
+// with {{template "name" .}}. Files under it are not generated on their
+// own; NewSet consumes them into the shared template set.
+const PartialsDir = "templates/_partials"
+
+// Set is a parsed collection of partials that individual files render
+// themselves against, so every file shares the same dependency-block
+// fragments instead of duplicating them.
+type Set struct {
+	partials *template.Template
+}
+
+// NewSet parses every *.tmpl fragment under PartialsDir in fsys into a
+// shared partial set. It's fine for that directory to not exist; a project
+// with no shared fragments just gets an empty set.
+func NewSet(fsys fs.FS) (*Set, error) {
+	partials := template.New("partials")
+
+	matches, err := fs.Glob(fsys, PartialsDir+"/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("templating: globbing partials: %w", err)
+	}
+	for _, p := range matches {
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("templating: reading partial %s: %w", p, err)
+		}
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+		if _, err := partials.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("templating: parsing partial %s: %w", p, err)
+		}
+	}
+
+	return &Set{partials: partials}, nil
+}
+
+// legacyPlaceholders maps the literal {{TOKEN}} substitution syntax this
+// package replaced (see the strings.ReplaceAll calls it removed from
+// main.go) to the equivalent text/template field reference. {{APP_NAME}}
+// isn't valid template syntax -- with no leading "." it parses as a call to
+// an undefined function -- so every file still written against the old
+// convention would fail to parse without this rewrite.
+var legacyPlaceholders = map[string]string{
+	"{{APP_NAME}}":       "{{.AppName}}",
+	"{{PACKAGE_NAME}}":   "{{.PackageName}}",
+	"{{MIN_SDK}}":        "{{.MinSdk}}",
+	"{{GRADLE_VERSION}}": "{{.GradleVersion}}",
+	"{{AGP_VERSION}}":    "{{.AGPVersion}}",
+	"{{KOTLIN_VERSION}}": "{{.KotlinVersion}}",
+	"{{CBOM_VERSION}}":   "{{.ComposeBomVersion}}",
+}
+
+// rewriteLegacyPlaceholders rewrites every legacy {{TOKEN}} occurrence in
+// body to its text/template field-reference equivalent before parsing.
+func rewriteLegacyPlaceholders(body []byte) []byte {
+	s := string(body)
+	for token, replacement := range legacyPlaceholders {
+		s = strings.ReplaceAll(s, token, replacement)
+	}
+	return []byte(s)
+}
+
+// Render executes the template body of a single file against data, with
+// access to every partial registered in the Set. data is usually a Data,
+// but callers that need extra fields (e.g. scaffold's per-module builds)
+// can pass a struct that embeds Data instead.
+func (s *Set) Render(name string, body []byte, data any) ([]byte, error) {
+	tmpl, err := s.partials.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("templating: cloning partial set for %s: %w", name, err)
+	}
+	tmpl, err = tmpl.New(name).Parse(string(rewriteLegacyPlaceholders(body)))
+	if err != nil {
+		return nil, fmt.Errorf("templating: parsing %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("templating: rendering %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// IsPartial reports whether relPath (relative to the templates/ root) is a
+// shared fragment that NewSet already consumed, so the generator's file
+// walk should skip emitting it as a standalone file.
+func IsPartial(relPath string) bool {
+	return relPath == "_partials" || strings.HasPrefix(relPath, "_partials/")
+}
+
+// IsModuleTemplate reports whether relPath (relative to the templates/
+// root) is one of the per-module-type build.gradle.kts templates under
+// _modules/ that scaffold.generateModule reads directly via fs.ReadFile and
+// renders against scaffold.ModuleData, not the plain Data every other file
+// gets. The generator's file walk should skip emitting it as a standalone
+// file, the same way it skips partials.
+func IsModuleTemplate(relPath string) bool {
+	return relPath == "_modules" || strings.HasPrefix(relPath, "_modules/")
+}
+
+// ParseFeatures turns the comma-free, already-split feature names (as
+// collected from a survey multi-select or the --features flag) into a
+// FeatureSet. Unknown names are ignored so older manifests keep working
+// against newer feature additions.
+func ParseFeatures(names []string) FeatureSet {
+	var fs FeatureSet
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "hilt":
+			fs.Hilt = true
+		case "room":
+			fs.Room = true
+		case "retrofit":
+			fs.Retrofit = true
+		case "nav", "navigation":
+			fs.Nav = true
+		}
+	}
+	return fs
+}
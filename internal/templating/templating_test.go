@@ -0,0 +1,135 @@
+package templating
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestSet_RenderFeatureCombinations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/_partials/dependencies.tmpl": &fstest.MapFile{
+			Data: []byte(`{{if .Features.Hilt}}implementation("com.google.dagger:hilt-android")
+{{end}}{{if .Features.Room}}implementation("androidx.room:room-runtime")
+{{end}}`),
+		},
+	}
+
+	set, err := NewSet(fsys)
+	if err != nil {
+		t.Fatalf("NewSet: %v", err)
+	}
+
+	body := []byte(`dependencies {
+{{template "dependencies" .}}}
+`)
+
+	cases := []struct {
+		name     string
+		features FeatureSet
+		want     string
+	}{
+		{
+			name:     "no features",
+			features: FeatureSet{},
+			want:     "dependencies {\n}\n",
+		},
+		{
+			name:     "hilt only",
+			features: FeatureSet{Hilt: true},
+			want:     "dependencies {\nimplementation(\"com.google.dagger:hilt-android\")\n}\n",
+		},
+		{
+			name:     "hilt and room",
+			features: FeatureSet{Hilt: true, Room: true},
+			want:     "dependencies {\nimplementation(\"com.google.dagger:hilt-android\")\nimplementation(\"androidx.room:room-runtime\")\n}\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := set.Render("build.gradle.kts", body, Data{Features: tc.features})
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if string(out) != tc.want {
+				t.Errorf("got %q, want %q", out, tc.want)
+			}
+		})
+	}
+}
+
+// TestSet_RenderLegacyPlaceholders guards against the tokens the pre-series
+// strings.ReplaceAll calls used to substitute -- {{APP_NAME}} and friends
+// aren't valid text/template syntax on their own, so a real Gradle file
+// still written against that convention must keep rendering.
+func TestSet_RenderLegacyPlaceholders(t *testing.T) {
+	set, err := NewSet(fstest.MapFS{})
+	if err != nil {
+		t.Fatalf("NewSet: %v", err)
+	}
+
+	body := []byte(`android {
+    namespace = "{{PACKAGE_NAME}}"
+    compileSdk = {{MIN_SDK}}
+}
+
+plugins {
+    id("com.android.application") version "{{AGP_VERSION}}"
+    kotlin("android") version "{{KOTLIN_VERSION}}"
+}
+
+// {{APP_NAME}} / {{GRADLE_VERSION}} / {{CBOM_VERSION}}
+`)
+
+	data := Data{
+		AppName:           "Mad",
+		PackageName:       "com.example.myapp",
+		MinSdk:            "24",
+		GradleVersion:     "9.2.1",
+		AGPVersion:        "8.13.2",
+		KotlinVersion:     "2.3.0",
+		ComposeBomVersion: "2025.12.01",
+	}
+
+	out, err := set.Render("app/build.gradle.kts", body, data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := `android {
+    namespace = "com.example.myapp"
+    compileSdk = 24
+}
+
+plugins {
+    id("com.android.application") version "8.13.2"
+    kotlin("android") version "2.3.0"
+}
+
+// Mad / 9.2.1 / 2025.12.01
+`
+	if string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestParseFeatures(t *testing.T) {
+	got := ParseFeatures([]string{"Hilt", " room ", "retrofit", "navigation", "unknown"})
+	want := FeatureSet{Hilt: true, Room: true, Retrofit: true, Nav: true}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestIsPartial(t *testing.T) {
+	cases := map[string]bool{
+		"_partials":                   true,
+		"_partials/dependencies.tmpl": true,
+		"app/build.gradle.kts":        false,
+	}
+	for relPath, want := range cases {
+		if got := IsPartial(relPath); got != want {
+			t.Errorf("IsPartial(%q) = %v, want %v", relPath, got, want)
+		}
+	}
+}
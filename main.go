@@ -1,7 +1,9 @@
 package main
 
 import (
+    "context"
     "embed"
+    "flag"
     "fmt"
     "io/fs"
     "os"
@@ -12,17 +14,16 @@ import (
     "runtime"
 
     "github.com/AlecAivazis/survey/v2"
+    "github.com/yahyatinani/droid-cli/internal/androidenv"
+    "github.com/yahyatinani/droid-cli/internal/bootstrap"
+    "github.com/yahyatinani/droid-cli/internal/config"
+    "github.com/yahyatinani/droid-cli/internal/scaffold"
+    "github.com/yahyatinani/droid-cli/internal/templating"
 )
 
 //go:embed templates/*
 var templateFS embed.FS
 
-type Config struct {
-    AppName     string
-    PackageName string
-    MinSdk      string
-}
-
 func validatePackageName(val interface{}) error {
     str, ok := val.(string)
     if !ok {
@@ -37,23 +38,20 @@ func validatePackageName(val interface{}) error {
     return nil
 }
 
-// FindJava locates the java executable
-func FindJava() string {
-    path, err := exec.LookPath("java")
+// FindJava locates a JDK via androidenv.DetectJDK, returning the path and
+// which source (e.g. JAVA_HOME, PATH) produced it.
+func FindJava() (path, source string) {
+    path, source, err := androidenv.DetectJDK()
     if err != nil {
-        return "Not Found"
+        return "Not Found", ""
     }
-    return path
+    return path, source
 }
 
-// FindSDK checks standard Android environment variables
+// FindSDK locates the Android SDK via androidenv.DetectSDK.
 func FindSDK() string {
-    // Check both standard variables
-    path := os.Getenv("ANDROID_HOME")
-    if path == "" {
-        path = os.Getenv("ANDROID_SDK_ROOT")
-    }
-    if path == "" {
+    path, _, err := androidenv.DetectSDK()
+    if err != nil {
         return "Not Found (Check ANDROID_HOME)"
     }
     return path
@@ -76,100 +74,36 @@ const (
     MinSdk            = "24"
 )
 
-func main() {
-    // --- Environment Check ---
-    fmt.Println("🔍 Checking Environment...")
-
-    javaPath := FindJava()
-    sdkPath := FindSDK()
-    gradlePath := FindGradle()
+// generateProject is the pure, testable core of the generation pipeline:
+// config in, rendered files on disk out. It takes no flags, runs no
+// prompts, and prints no environment banner, so a headless --yes run (and
+// its tests) can drive it directly against a fixture templateFS.
+func generateProject(templateFS fs.FS, answers config.Config, outputDir string, sdkPath string, sdkErr error) error {
+    targetPackagePath := strings.ReplaceAll(answers.PackageName, ".", "/")
 
-    // Print status
-    if javaPath == "Not Found" {
-        fmt.Println("⚠️  Java:", javaPath)
-    } else {
-        fmt.Println("✅ Java:", javaPath)
-    }
-
-    if strings.Contains(sdkPath, "Not Found") {
-        fmt.Println("⚠️  Android SDK:", sdkPath)
-    } else {
-        fmt.Println("✅ Android SDK:", sdkPath)
-    }
-
-    if strings.Contains(gradlePath, "Not Found") {
-        fmt.Println("ℹ️  Gradle:", gradlePath)
-    } else {
-        fmt.Println("✅  Gradle:", gradlePath)
-    }
-    
-    fmt.Println("")
-    fmt.Println("🔨 Build System Versions")
-    fmt.Println("ℹ️  Target AGP Version:", AGPVersion)
-    fmt.Println("ℹ️  Target Kotlin Version:", KotlinVersion)
-    fmt.Println("ℹ️  Target Gradle Wrapper:", GradleVersion)
-
-    fmt.Println(strings.Repeat("-", 50))
-    // -------------------------------
-
-	var answers Config
-	
-	qs := []*survey.Question{
-        {
-            Name:     "AppName",
-            Prompt:   &survey.Input{Message: "What is the App Name?", Default: "Mad"},
-            Validate: survey.Required,
-        },
-        {
-            Name:     "PackageName",
-            Prompt:   &survey.Input{Message: "Package Name?", Default: "com.example.myapp"},
-            Validate: survey.ComposeValidators(survey.Required, validatePackageName),
-        },
-        {
-            Name: "MinSdk",
-            Prompt: &survey.Select{
-                Message: "Select minimum SDK:",
-                Options: []string{"21", "22", "23", "24", "25", "26", "27", 
-                                  "28", "29", "30", "31", "32", "33", "34",
-                                  "35", "36"},
-                Default: MinSdk,
-            },
-        },
+    // Make the root dir
+    if err := os.MkdirAll(outputDir, 0755); err != nil {
+        return fmt.Errorf("creating project directory: %w", err)
     }
 
-	err := survey.Ask(qs, &answers)
+    templateSet, err := templating.NewSet(templateFS)
     if err != nil {
-        fmt.Println("❌ Error:", err)
-        return
+        return fmt.Errorf("loading templates: %w", err)
     }
 
-	outputDir := answers.AppName
-
-    if _, err := os.Stat(outputDir); err == nil {
-        overwrite := false
-        prompt := &survey.Confirm{
-            Message: fmt.Sprintf("Directory '%s' already exists. Overwrite?", outputDir),
-        }
-        survey.AskOne(prompt, &overwrite)
-        if !overwrite {
-            fmt.Println("❌ Operation cancelled.")
-            return
-        }
-        // Remove existing directory
-        os.RemoveAll(outputDir)
+    features := templating.ParseFeatures(answers.Features)
+    templateData := templating.Data{
+        AppName:           answers.AppName,
+        PackageName:       answers.PackageName,
+        MinSdk:            answers.MinSdk,
+        GradleVersion:     GradleVersion,
+        AGPVersion:        AGPVersion,
+        KotlinVersion:     KotlinVersion,
+        ComposeBomVersion: ComposeBomVersion,
+        Features:          features,
     }
 
-	targetPackagePath := strings.ReplaceAll(answers.PackageName, ".", "/")
-    
-	fmt.Printf("🚀 Generating %s in ./%s ...\n", answers.AppName, outputDir)
-
-    // Make the root dir
-    if err := os.MkdirAll(outputDir, 0755); err != nil {
-        fmt.Printf("\n❌ Failed to create project directory: %v\n", err)
-        return
-    }
-
-	err = fs.WalkDir(templateFS, "templates", func(path string, d fs.DirEntry, err error) error {	
+	err = fs.WalkDir(templateFS, "templates", func(path string, d fs.DirEntry, err error) error {
         if err != nil {
             return err
         }
@@ -181,6 +115,25 @@ func main() {
         // Remove the "templates/" prefix to get the relative path
         relPath := strings.TrimPrefix(path, "templates/")
 
+        if templating.IsPartial(relPath) {
+            // Shared fragments are rendered into regular files via
+            // {{template "name" .}}, not emitted as files themselves.
+            if d.IsDir() {
+                return fs.SkipDir
+            }
+            return nil
+        }
+
+        if templating.IsModuleTemplate(relPath) {
+            // Per-module-type build.gradle.kts templates are rendered
+            // directly by scaffold.generateModule against ModuleData, not
+            // by this walk against the plain Data every other file gets.
+            if d.IsDir() {
+                return fs.SkipDir
+            }
+            return nil
+        }
+
         // Define our hardcoded template paths
         const sourcePackagePath = "com/example/rockstarcompose"
         const javaSourceBase = "app/src/main/java/"
@@ -198,13 +151,13 @@ func main() {
                     // Reconstruct path: Prefix + NewPackage + Suffix
                     prefix := relPath[:idx]
                     suffix := relPath[idx+len(sourcePackagePath):]
-                    
+
                     newRelPath := prefix + targetPackagePath + suffix
                     destPath = filepath.Join(outputDir, newRelPath)
                 }
             // we are likely visiting the parent directories: "com" or "com/example"
             } else if answers.PackageName != "com.example.app" {
-                // If the user's package is DIFFERENT from the template, we must skip 
+                // If the user's package is DIFFERENT from the template, we must skip
                 // creating the old template parents (com/example) to avoid empty junk folders.
                 if relPath == "app/src/main/java/com" || relPath == "app/src/main/java/com/example" {
                     // Return nil to skip this directory entry
@@ -212,7 +165,7 @@ func main() {
                 }
             }
         }
-        
+
         if d.IsDir() {
             // Create directory
            //  fmt.Println("destPath", destPath)
@@ -220,37 +173,33 @@ func main() {
         }
 
         // Read file content
-        content, err := templateFS.ReadFile(path)
+        content, err := fs.ReadFile(templateFS, path)
         if err != nil {
             return err
         }
 
         // Handle Permissions
         // Default to read/write for owner/group
-        perm := fs.FileMode(0644) 
+        perm := fs.FileMode(0644)
 
         if filepath.Base(destPath) == "gradlew" {
-            perm = 0755 
+            perm = 0755
         }
 
-        // Replace placeholders in the file content
-        fileStr := string(content)
-        fileStr = strings.ReplaceAll(fileStr, "{{APP_NAME}}", answers.AppName)
-        fileStr = strings.ReplaceAll(fileStr, "{{PACKAGE_NAME}}", answers.PackageName)
-        fileStr = strings.ReplaceAll(fileStr, "{{MIN_SDK}}", answers.MinSdk)
-
-        fileStr = strings.ReplaceAll(fileStr, "{{GRADLE_VERSION}}", GradleVersion)
-        fileStr = strings.ReplaceAll(fileStr, "{{AGP_VERSION}}", AGPVersion)
-        fileStr = strings.ReplaceAll(fileStr, "{{KOTLIN_VERSION}}", KotlinVersion)
-        fileStr = strings.ReplaceAll(fileStr, "{{CBOM_VERSION}}", ComposeBomVersion)
+        // Render the file against templateData so it can reference
+        // {{.AppName}}, {{.PackageName}}, and conditionally pull in
+        // feature dependency blocks via {{if .Features.Hilt}}...{{end}}.
+        rendered, err := templateSet.Render(relPath, content, templateData)
+        if err != nil {
+            return err
+        }
 
         // Write to destination
-        return os.WriteFile(destPath, []byte(fileStr), perm)
+        return os.WriteFile(destPath, rendered, perm)
     })
 
     if err != nil {
-        fmt.Printf("\n❌ Failed to generate project: %v\n", err)
-        return
+        return fmt.Errorf("generating project: %w", err)
     }
 
     // 4. Create a helpful README (Optional but nice)
@@ -268,8 +217,188 @@ Generated by droid-cli.
    or
    adb shell monkey -p your.package.id -c android.intent.category.LAUNCHER 1
 `, answers.AppName)
-    
+
     os.WriteFile(filepath.Join(outputDir, "README.md"), []byte(readmeContent), 0644)
+
+    // 5. Point Gradle at the detected SDK so a build works even if the
+    // shell that runs gradlew doesn't have ANDROID_HOME set.
+    if sdkErr == nil {
+        if err := androidenv.WriteLocalProperties(outputDir, sdkPath); err != nil {
+            fmt.Printf("⚠️  Failed to write local.properties: %v\n", err)
+        }
+    } else {
+        fmt.Println("⚠️  Skipping local.properties: no Android SDK detected")
+    }
+
+    // 6. Scaffold any extra modules the user asked for (app,core-ui,
+    // data:library,domain:library) on top of the base project.
+    if len(answers.Modules) > 0 {
+        if err := scaffold.Generate(templateSet, templateFS, outputDir, answers.Modules, templateData); err != nil {
+            return fmt.Errorf("scaffolding modules: %w", err)
+        }
+    }
+
+    return nil
+}
+
+func main() {
+    appNameFlag := flag.String("app-name", "", "application display name (skips that prompt when set)")
+    packageFlag := flag.String("package", "", "Android package name, e.g. com.example.app (skips that prompt when set)")
+    minSdkFlag := flag.String("min-sdk", "", "minimum supported SDK level (skips that prompt when set)")
+    outputFlag := flag.String("output", "", "directory to generate the project into (defaults to the app name)")
+    featuresFlag := flag.String("features", "", "comma-separated feature list, e.g. hilt,room,nav")
+    modulesFlag := flag.String("modules", "", "comma-separated module list for a multi-module project, e.g. app,core-ui,data:library,domain:library")
+    yesFlag := flag.Bool("yes", false, "assume yes for confirmations such as overwriting the output directory (the bootstrap prompt is skipped; use --bootstrap to run it); fail instead of prompting for anything still missing")
+    configFlag := flag.String("config", "", "path to a YAML or JSON manifest with the same fields as the flags above")
+    bootstrapFlag := flag.Bool("bootstrap", false, "materialize the Gradle wrapper (and run ./gradlew help) right after generation")
+    flag.Parse()
+
+    cliFlags := config.Flags{
+        AppName:     *appNameFlag,
+        PackageName: *packageFlag,
+        MinSdk:      *minSdkFlag,
+        Output:      *outputFlag,
+        Features:    *featuresFlag,
+        Modules:     *modulesFlag,
+        Yes:         *yesFlag,
+        ConfigFile:  *configFlag,
+    }
+
+    resolved, sources, err := config.Resolve(cliFlags, "", os.Getenv)
+    if err != nil {
+        fmt.Println("❌", err)
+        return
+    }
+
+    // Validate the module graph before any directory gets created, so a bad
+    // --modules spec (duplicate names, no/multiple app modules, a dependency
+    // cycle) fails fast instead of leaving a half-generated project behind.
+    if len(resolved.Modules) > 0 {
+        if err := scaffold.Validate(resolved.Modules); err != nil {
+            fmt.Println("❌", err)
+            return
+        }
+    }
+
+    // --- Environment Check ---
+    fmt.Println("🔍 Checking Environment...")
+
+    javaPath, javaSource := FindJava()
+    sdkPath, sdkSource, sdkErr := androidenv.DetectSDK()
+    gradlePath := FindGradle()
+
+    // Print status
+    if javaPath == "Not Found" {
+        fmt.Println("⚠️  Java:", javaPath)
+    } else {
+        fmt.Printf("✅ Java: %s (via %s)\n", javaPath, javaSource)
+    }
+
+    if sdkErr != nil {
+        fmt.Println("⚠️  Android SDK: Not Found (Check ANDROID_HOME)")
+    } else {
+        fmt.Printf("✅ Android SDK: %s (via %s)\n", sdkPath, sdkSource)
+    }
+
+    if strings.Contains(gradlePath, "Not Found") {
+        fmt.Println("ℹ️  Gradle:", gradlePath)
+    } else {
+        fmt.Println("✅  Gradle:", gradlePath)
+    }
+    
+    fmt.Println("")
+    fmt.Println("🔨 Build System Versions")
+    fmt.Println("ℹ️  Target AGP Version:", AGPVersion)
+    fmt.Println("ℹ️  Target Kotlin Version:", KotlinVersion)
+    fmt.Println("ℹ️  Target Gradle Wrapper:", GradleVersion)
+
+    fmt.Println(strings.Repeat("-", 50))
+    // -------------------------------
+
+	answers := resolved
+
+	var requiredQs []*survey.Question
+    if sources["AppName"] == "default" {
+        requiredQs = append(requiredQs, &survey.Question{
+            Name:     "AppName",
+            Prompt:   &survey.Input{Message: "What is the App Name?", Default: answers.AppName},
+            Validate: survey.Required,
+        })
+    }
+    if sources["PackageName"] == "default" {
+        requiredQs = append(requiredQs, &survey.Question{
+            Name:     "PackageName",
+            Prompt:   &survey.Input{Message: "Package Name?", Default: answers.PackageName},
+            Validate: survey.ComposeValidators(survey.Required, validatePackageName),
+        })
+    }
+    if sources["MinSdk"] == "default" {
+        requiredQs = append(requiredQs, &survey.Question{
+            Name: "MinSdk",
+            Prompt: &survey.Select{
+                Message: "Select minimum SDK:",
+                Options: []string{"21", "22", "23", "24", "25", "26", "27",
+                                  "28", "29", "30", "31", "32", "33", "34",
+                                  "35", "36"},
+                Default: answers.MinSdk,
+            },
+        })
+    }
+
+    var optionalQs []*survey.Question
+    if sources["Features"] == "default" {
+        optionalQs = append(optionalQs, &survey.Question{
+            Name: "Features",
+            Prompt: &survey.MultiSelect{
+                Message: "Select features to include:",
+                Options: []string{"hilt", "room", "retrofit", "nav"},
+            },
+        })
+    }
+
+    if len(requiredQs) > 0 && answers.Yes {
+        fmt.Println("❌ --yes was set but required fields are still missing; pass --app-name/--package/--min-sdk or --config.")
+        return
+    }
+
+    if !answers.Yes {
+        qs := append(requiredQs, optionalQs...)
+        if len(qs) > 0 {
+            if err := survey.Ask(qs, &answers); err != nil {
+                fmt.Println("❌ Error:", err)
+                return
+            }
+        }
+    }
+
+	outputDir := answers.Output
+    if outputDir == "" {
+        outputDir = answers.AppName
+    }
+
+    if _, err := os.Stat(outputDir); err == nil {
+        overwrite := answers.Yes
+        if !overwrite {
+            prompt := &survey.Confirm{
+                Message: fmt.Sprintf("Directory '%s' already exists. Overwrite?", outputDir),
+            }
+            survey.AskOne(prompt, &overwrite)
+        }
+        if !overwrite {
+            fmt.Println("❌ Operation cancelled.")
+            return
+        }
+        // Remove existing directory
+        os.RemoveAll(outputDir)
+    }
+
+	fmt.Printf("🚀 Generating %s in ./%s ...\n", answers.AppName, outputDir)
+
+    if err := generateProject(templateFS, answers, outputDir, sdkPath, sdkErr); err != nil {
+        fmt.Printf("\n❌ %v\n", err)
+        return
+    }
+
     fmt.Println("\n✅ Success!")
 
     absPath, err := filepath.Abs(outputDir)
@@ -286,4 +415,20 @@ Generated by droid-cli.
 	default:
 		fmt.Println("🔨 $ ./gradlew buildDebug")
 	}
+
+    // 7. Optionally materialize the Gradle wrapper now instead of leaving
+    // that to the user's first `./gradlew` invocation.
+    runBootstrap := *bootstrapFlag
+    if !runBootstrap && !answers.Yes {
+        survey.AskOne(&survey.Confirm{Message: "Run Gradle wrapper bootstrap now?"}, &runBootstrap)
+    }
+    if runBootstrap {
+        fmt.Println("")
+        opts := bootstrap.Options{GradleVersion: GradleVersion, Task: "help"}
+        if err := bootstrap.Run(context.Background(), outputDir, opts); err != nil {
+            fmt.Printf("⚠️  Bootstrap failed: %v\n", err)
+        } else {
+            fmt.Println("✅ Bootstrap complete")
+        }
+    }
 }
\ No newline at end of file
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yahyatinani/droid-cli/internal/config"
+)
+
+// fixtureTemplateFS is a small stand-in for the real templates/ tree: one
+// source file under the hardcoded package path, a shared partial, and a
+// per-module-type build.gradle.kts.tmpl under _modules -- enough surface to
+// exercise config -> template render -> scaffold end to end headlessly.
+func fixtureTemplateFS() fstest.MapFS {
+	return fstest.MapFS{
+		"templates/app/src/main/java/com/example/rockstarcompose/MainActivity.kt": &fstest.MapFile{
+			Data: []byte(`package {{.PackageName}}
+
+class MainActivity
+`),
+		},
+		"templates/app/build.gradle.kts": &fstest.MapFile{
+			Data: []byte(`dependencies {
+{{template "dependencies" .}}}
+`),
+		},
+		"templates/settings.gradle.kts": &fstest.MapFile{
+			Data: []byte("rootProject.name = \"{{.AppName}}\"\n"),
+		},
+		"templates/_partials/dependencies.tmpl": &fstest.MapFile{
+			Data: []byte(`{{if .Features.Hilt}}implementation("com.google.dagger:hilt-android")
+{{end}}`),
+		},
+		"templates/_modules/library/build.gradle.kts.tmpl": &fstest.MapFile{
+			Data: []byte(`plugins { id("com.android.library") }
+// module: {{.Module.Name}}
+`),
+		},
+	}
+}
+
+// TestGenerateProject_BaseProject is a headless --yes-style smoke test
+// driving config -> template render, the pipeline covered by
+// internal/config's precedence tests but never exercised end to end. It
+// would have caught the base walk copying _modules/<type>/*.tmpl verbatim
+// into every generated project.
+func TestGenerateProject_BaseProject(t *testing.T) {
+	answers := config.Config{
+		AppName:     "Mad",
+		PackageName: "com.example.myapp",
+		MinSdk:      "24",
+		Yes:         true,
+	}
+	outputDir := filepath.Join(t.TempDir(), "Mad")
+
+	if err := generateProject(fixtureTemplateFS(), answers, outputDir, "", nil); err != nil {
+		t.Fatalf("generateProject: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outputDir, "app/src/main/java/com/example/myapp/MainActivity.kt"))
+	if err != nil {
+		t.Fatalf("reading rendered MainActivity.kt: %v", err)
+	}
+	if !strings.Contains(string(got), "package com.example.myapp") {
+		t.Errorf("MainActivity.kt = %q, want package com.example.myapp", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "_modules", "library", "build.gradle.kts.tmpl")); !os.IsNotExist(err) {
+		t.Errorf("_modules template was copied into the output (err = %v), want it skipped like a partial", err)
+	}
+}
+
+// TestGenerateProject_WithModules exercises the --modules path: the app
+// module's build.gradle.kts must survive untouched (scaffold.Generate
+// skips app-type modules), and the library module must render against
+// scaffold.ModuleData, which the base walk can't provide.
+func TestGenerateProject_WithModules(t *testing.T) {
+	answers := config.Config{
+		AppName:     "Mad",
+		PackageName: "com.example.myapp",
+		MinSdk:      "24",
+		Yes:         true,
+		Modules: []config.ModuleSpec{
+			{Name: "app", Type: config.ModuleApp},
+			{Name: "core-ui", Type: config.ModuleLibrary},
+		},
+	}
+	outputDir := filepath.Join(t.TempDir(), "Mad")
+
+	if err := generateProject(fixtureTemplateFS(), answers, outputDir, "", nil); err != nil {
+		t.Fatalf("generateProject: %v", err)
+	}
+
+	appBuild, err := os.ReadFile(filepath.Join(outputDir, "app", "build.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading app/build.gradle.kts: %v", err)
+	}
+	if string(appBuild) != "dependencies {\n}\n" {
+		t.Errorf("app/build.gradle.kts = %q, want the base walk's rendering left untouched", appBuild)
+	}
+
+	libBuild, err := os.ReadFile(filepath.Join(outputDir, "core-ui", "build.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading core-ui/build.gradle.kts: %v", err)
+	}
+	if !strings.Contains(string(libBuild), "module: core-ui") {
+		t.Errorf("core-ui/build.gradle.kts = %q, want it rendered against ModuleData", libBuild)
+	}
+
+	settings, err := os.ReadFile(filepath.Join(outputDir, "settings.gradle.kts"))
+	if err != nil {
+		t.Fatalf("reading settings.gradle.kts: %v", err)
+	}
+	if !strings.Contains(string(settings), `include(":core-ui")`) {
+		t.Errorf("settings.gradle.kts = %q, want core-ui included", settings)
+	}
+}